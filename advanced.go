@@ -5,12 +5,23 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/url"
 	"sync"
 	"time"
+
+	"github.com/jumayevgadam/golb/healthcheck"
 )
 
-const healthCheckerTime = 10 * time.Second
+const (
+	healthCheckerTime = 10 * time.Second
+
+	// defaultDrainTimeout bounds how long RemoveServer waits for a
+	// backend's in-flight requests to finish before closing it.
+	defaultDrainTimeout = 5 * time.Second
+	// drainPollInterval is how often RemoveServer polls GetLoad while draining.
+	drainPollInterval = 10 * time.Millisecond
+)
 
 // backendHeap implements a min-heap based on backend load and address.
 type backendHeap []*BackendImpl
@@ -51,149 +62,430 @@ func (h *backendHeap) Pop() interface{} {
 	return x
 }
 
-// Ensure AdvancedBalancer implements Backend interface.
-var _ Backend = (*AdvancedBalancer)(nil)
+// Ensure AdvancedBalancer implements the Backend and Balancer interfaces.
+var (
+	_ Backend  = (*AdvancedBalancer)(nil)
+	_ Balancer = (*AdvancedBalancer)(nil)
+)
 
-// AdvancedBalancer implements a heap-based load balancer with health checks.
+// AdvancedBalancer implements a heap-based load balancer backed by active
+// health-check probes (see the healthcheck package).
 type AdvancedBalancer struct {
-	backends []*BackendImpl // all unhealthy backends.
+	backends []*BackendImpl // all known backends.
 	heap     *backendHeap   // Min-Heap of healthy backend servers.
 	mu       sync.Mutex     // Mutex for safe concurrent access.
-	stopChan chan struct{}  // Channel to stop health checker.
+
+	probeOpts healthcheck.Options            // template options applied to every backend's prober.
+	probers   map[string]*healthcheck.Prober // addr -> prober, one goroutine per backend.
+
+	retry      RetryPolicy    // retry/backoff policy applied by Invoke, and to gate DOWN re-probing.
+	downStreak map[string]int // addr -> consecutive DOWN transitions, drives probe backoff.
+
+	metrics      Metrics       // applied to every backend, including ones added later.
+	drainTimeout time.Duration // how long RemoveServer waits for a backend to drain.
 }
 
-// NewAdvancedLoadBalancer initializes an AdvancedBalancer from a list of backend URLs.
-func NewAdvancedLoadBalancer(urls []*url.URL) *AdvancedBalancer {
-	backends := make([]*BackendImpl, len(urls))
-	h := &backendHeap{}
-	heap.Init(h)
+// defaultProbeOptions returns the options used until the caller customizes
+// health-check behavior via SetBackendsConfiguration.
+func defaultProbeOptions() healthcheck.Options {
+	return healthcheck.Options{
+		Scheme:    "http",
+		Path:      "/",
+		Method:    "GET",
+		Timeout:   httpClientTimeOut,
+		Interval:  healthCheckerTime,
+		RiseCount: 2,
+		FallCount: maxFailureCount,
+	}
+}
 
+// NewAdvancedLoadBalancer initializes an AdvancedBalancer from a list of
+// HTTPS backend URLs and starts one active health-check probe goroutine per
+// backend. For gRPC backends, build BackendImpls with NewGRPCBackend and use
+// NewAdvancedLoadBalancerFromBackends instead. An optional RetryPolicy
+// overrides DefaultRetryPolicy().
+func NewAdvancedLoadBalancer(urls []*url.URL, policy ...RetryPolicy) *AdvancedBalancer {
+	backends := make([]*BackendImpl, len(urls))
 	for i, u := range urls {
 		backends[i] = NewBackend(u.Host)
-		// only add healthy backends to heap initially.
-		if backends[i].IsHealthy() {
-			heap.Push(h, backends[i])
-		}
 	}
 
+	return NewAdvancedLoadBalancerFromBackends(backends, policy...)
+}
+
+// NewAdvancedLoadBalancerFromBackends initializes an AdvancedBalancer from
+// already-constructed backends, allowing a mix of HTTP(S) and gRPC backends.
+func NewAdvancedLoadBalancerFromBackends(backends []*BackendImpl, policy ...RetryPolicy) *AdvancedBalancer {
+	h := &backendHeap{}
+	heap.Init(h)
+
 	balancer := &AdvancedBalancer{
-		heap:     h,
-		backends: backends,
-		stopChan: make(chan struct{}),
+		heap:         h,
+		backends:     backends,
+		probeOpts:    defaultProbeOptions(),
+		probers:      make(map[string]*healthcheck.Prober, len(backends)),
+		retry:        resolveRetryPolicy(policy),
+		downStreak:   make(map[string]int, len(backends)),
+		metrics:      NoopMetrics,
+		drainTimeout: defaultDrainTimeout,
 	}
 
-	// start health checker in separate goroutine.
-	go balancer.healthChecker()
+	for _, backend := range backends {
+		balancer.startProber(backend)
+	}
 
 	return balancer
 }
 
-func (b *AdvancedBalancer) healthChecker() {
-	ticker := time.NewTicker(healthCheckerTime) // check every 10 seconds.
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			b.mu.Lock()
-			for _, backend := range b.backends {
-				if !backend.IsHealthy() {
-					continue // we need to skip if backend unhealthy.
-				}
-
-				if backend.IsHealthy() {
-					alreadyInHeap := false
-
-					for _, bh := range *b.heap { // bh is backendInHeap and we need to check that backend already stored in heap or not.
-						if bh == backend {
-							alreadyInHeap = true
-							break
-						}
-					}
-					// if not pushed to heap yet, then push healthy backend to heap.
-					if !alreadyInHeap {
-						heap.Push(b.heap, backend)
-					}
-				}
+// startProber creates and starts a Prober for backend, wiring its UP/DOWN
+// transitions back into the balancer's heap. Safe to call after
+// construction, e.g. from UpsertServer.
+func (b *AdvancedBalancer) startProber(backend *BackendImpl) {
+	// The prober now owns this backend's UP/DOWN transitions; IsHealthy's
+	// own time-based recovery would otherwise race with it.
+	backend.disablePassiveRecovery()
+
+	b.mu.Lock()
+	opts := b.probeOpts
+
+	if backend.protocol == ProtocolGRPC {
+		opts.Protocol = string(ProtocolGRPC)
+		opts.ServiceName = backend.serviceName
+		opts.GRPCConn = backend.grpcConn
+	} else {
+		opts.Hostname, opts.Port = splitHostPort(backend.addr)
+	}
+	b.mu.Unlock()
+
+	prober := healthcheck.NewProber(opts, func(up bool) {
+		b.onHealthChange(backend, up)
+	})
+
+	b.mu.Lock()
+	b.probers[backend.addr] = prober
+	b.mu.Unlock()
+
+	prober.Start()
+}
+
+// onHealthChange runs on a backend's probe goroutine whenever its health
+// state flips. A DOWN backend is re-probed with the same exponential
+// backoff used for Invoke retries, so a backend that keeps failing is
+// checked less often instead of hammering it at the base interval.
+func (b *AdvancedBalancer) onHealthChange(backend *BackendImpl, up bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if up {
+		backend.MarkHealthy()
+		b.pushIfAbsent(backend)
+
+		if b.downStreak[backend.addr] > 0 {
+			b.downStreak[backend.addr] = 0
+
+			if prober, ok := b.probers[backend.addr]; ok {
+				prober.SetInterval(b.probeOpts.Interval)
 			}
-			b.mu.Unlock()
-		case <-b.stopChan:
+		}
+
+		return
+	}
+
+	backend.MarkUnhealthy()
+	log.Printf("[WARN] backend %s marked DOWN by health check", backend.addr)
+	b.removeFromHeap(backend)
+
+	streak := b.downStreak[backend.addr]
+	b.downStreak[backend.addr] = streak + 1
+
+	if prober, ok := b.probers[backend.addr]; ok {
+		delay := b.retry.backoff(streak)
+		if delay < b.probeOpts.Interval {
+			delay = b.probeOpts.Interval
+		}
+
+		prober.SetInterval(delay)
+	}
+}
+
+// pushIfAbsent pushes backend onto the heap unless it is already there or
+// has been removed by RemoveServer. Caller must hold b.mu.
+func (b *AdvancedBalancer) pushIfAbsent(backend *BackendImpl) {
+	registered := false
+	for _, bb := range b.backends {
+		if bb == backend {
+			registered = true
+			break
+		}
+	}
+
+	if !registered {
+		return
+	}
+
+	for _, bh := range *b.heap {
+		if bh == backend {
 			return
 		}
 	}
+
+	heap.Push(b.heap, backend)
 }
 
-// StopHealthChecker stops the health checker goroutine.
-func (b *AdvancedBalancer) StopHealthChecker() {
-	close(b.stopChan)
+// removeFromHeap removes backend from the heap if present.
+// Caller must hold b.mu.
+func (b *AdvancedBalancer) removeFromHeap(backend *BackendImpl) {
+	for i, bh := range *b.heap {
+		if bh == backend {
+			heap.Remove(b.heap, i)
+			return
+		}
+	}
 }
 
-// GetNextServer returns the next available healthy backend based on lowest load.
-func (b *AdvancedBalancer) GetNextServer() *BackendImpl {
+// SetBackendsConfiguration reconfigures every backend's health-check probe
+// with opts, without restarting the balancer or losing in-flight requests.
+func (b *AdvancedBalancer) SetBackendsConfiguration(opts healthcheck.Options) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	b.probeOpts = opts
 
-	// If heap is empty, repopulate it with healthy backends.
-	if b.heap.Len() == 0 {
-		for _, backend := range b.backends {
-			if backend.IsHealthy() {
-				heap.Push(b.heap, backend)
-			}
+	type reconfigure struct {
+		prober *healthcheck.Prober
+		opts   healthcheck.Options
+	}
+
+	pending := make([]reconfigure, 0, len(b.backends))
+	for _, backend := range b.backends {
+		perBackend := opts
+
+		if backend.protocol == ProtocolGRPC {
+			perBackend.Protocol = string(ProtocolGRPC)
+			perBackend.ServiceName = backend.serviceName
+			perBackend.GRPCConn = backend.grpcConn
+		} else {
+			perBackend.Hostname, perBackend.Port = splitHostPort(backend.addr)
+		}
+
+		if prober, ok := b.probers[backend.addr]; ok {
+			pending = append(pending, reconfigure{prober: prober, opts: perBackend})
 		}
 	}
+	b.mu.Unlock()
 
-	if b.heap.Len() == 0 {
-		return nil // no healthy backends available.
+	// Reconfigure blocks on Prober.Stop, which waits for the probe
+	// goroutine to exit. That goroutine's onChange callback can call back
+	// into b.onHealthChange, which needs b.mu, so we must not hold it here.
+	for _, r := range pending {
+		r.prober.Reconfigure(r.opts)
 	}
-	// Select the healthiest backend with lowest load.
-	var selected *BackendImpl
-
-	for b.heap.Len() > 0 {
-		// we remove the least loaded backend from heap.
-		backend, ok := heap.Pop(b.heap).(*BackendImpl)
-		if !ok {
-			log.Println("heap.Pop(b.heap).(*BackendImpl): type assertion error")
-			continue // skip to next backend in heap.
+}
+
+// SetMetrics wires m into every backend, including ones added afterwards.
+func (b *AdvancedBalancer) SetMetrics(m Metrics) {
+	b.mu.Lock()
+	b.metrics = m
+	backends := append([]*BackendImpl(nil), b.backends...)
+	b.mu.Unlock()
+
+	for _, backend := range backends {
+		backend.SetMetrics(m)
+	}
+}
+
+// SetDrainTimeout overrides how long RemoveServer waits for a backend's
+// in-flight requests to finish before closing it. The default is
+// defaultDrainTimeout.
+func (b *AdvancedBalancer) SetDrainTimeout(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.drainTimeout = d
+}
+
+// StopHealthChecker stops every backend's health-check probe goroutine and
+// closes any persistent gRPC connections opened for them.
+func (b *AdvancedBalancer) StopHealthChecker() {
+	b.mu.Lock()
+	probers := make([]*healthcheck.Prober, 0, len(b.probers))
+	for _, prober := range b.probers {
+		probers = append(probers, prober)
+	}
+	backends := append([]*BackendImpl(nil), b.backends...)
+	b.mu.Unlock()
+
+	for _, prober := range probers {
+		prober.Stop()
+	}
+
+	for _, backend := range backends {
+		if err := backend.Close(); err != nil {
+			log.Printf("backend %s: error closing connection: %v", backend.addr, err)
 		}
+	}
+}
 
-		if backend.IsHealthy() {
-			selected = backend
-			break
+// UpsertServer adds u if it is not already present, and starts its
+// health-check probe. Calling it again for an address that already exists
+// is a no-op.
+func (b *AdvancedBalancer) UpsertServer(u *url.URL, _ ...ServerOption) error {
+	b.mu.Lock()
+	for _, backend := range b.backends {
+		if backend.addr == u.Host {
+			b.mu.Unlock()
+			return nil
 		}
 	}
+	metrics := b.metrics
+	b.mu.Unlock()
+
+	backend := NewBackend(u.Host)
+	backend.SetMetrics(metrics)
 
-	return selected
+	b.mu.Lock()
+	b.backends = append(b.backends, backend)
+	b.pushIfAbsent(backend)
+	b.mu.Unlock()
+
+	b.startProber(backend)
+
+	return nil
 }
 
-// Invoke sends the request to the next available backend.
-// Marks backend as unhealthy if it fails repeatedly.
-func (b *AdvancedBalancer) Invoke(ctx context.Context, req Request) (Response, error) {
-	backend := b.GetNextServer()
-	if backend == nil {
-		return nil, ErrBackendServersEmpty
+// RemoveServer removes the backend for u from the balancer, stops
+// health-checking it, waits (bounded by b.drainTimeout) for its in-flight
+// requests to finish, then closes it. Returns ErrBackendNotFound if u is not
+// registered.
+func (b *AdvancedBalancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+
+	idx := -1
+	for i, backend := range b.backends {
+		if backend.addr == u.Host {
+			idx = i
+			break
+		}
+	}
+
+	if idx == -1 {
+		b.mu.Unlock()
+		return ErrBackendNotFound
+	}
+
+	backend := b.backends[idx]
+
+	updated := make([]*BackendImpl, 0, len(b.backends)-1)
+	updated = append(updated, b.backends[:idx]...)
+	updated = append(updated, b.backends[idx+1:]...)
+	b.backends = updated
+
+	b.removeFromHeap(backend)
+	delete(b.downStreak, backend.addr)
+
+	prober := b.probers[backend.addr]
+	delete(b.probers, backend.addr)
+
+	timeout := b.drainTimeout
+
+	// Unlock before Stop/drain: Stop blocks until the prober's goroutine
+	// exits, and that goroutine's onHealthChange callback acquires b.mu.
+	b.mu.Unlock()
+
+	if prober != nil {
+		prober.Stop()
+	}
+
+	b.drainBackend(backend, timeout)
+
+	if err := backend.Close(); err != nil {
+		log.Printf("backend %s: error closing connection: %v", backend.addr, err)
 	}
 
-	resp, err := backend.Invoke(ctx, req)
+	return nil
+}
+
+// drainBackend polls backend's in-flight request count until it reaches
+// zero or timeout elapses.
+func (b *AdvancedBalancer) drainBackend(backend *BackendImpl, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+
+	for backend.GetLoad() > 0 && time.Now().Before(deadline) {
+		time.Sleep(drainPollInterval)
+	}
+}
 
+// Servers lists the currently registered backend URLs.
+func (b *AdvancedBalancer) Servers() []*url.URL {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	urls := make([]*url.URL, len(b.backends))
+	for i, backend := range b.backends {
+		urls[i] = &url.URL{Host: backend.addr}
+	}
+
+	return urls
+}
+
+// splitHostPort splits a "host:port" backend address, returning addr
+// unchanged as the hostname if it carries no port.
+func splitHostPort(addr string) (hostname, port string) {
+	hostname, port, err := net.SplitHostPort(addr)
 	if err != nil {
-		backend.mu.Lock() // we need to lock backend's own to safely update failure count.
-		backend.failureCount++
+		return addr, ""
+	}
 
-		if backend.failureCount >= maxFailureCount {
-			backend.MarkUnhealthy() // Mark backend as unhealthy after maxFailureCount.
-		} else {
-			// if not maxFailures yet, push to heap.
-			heap.Push(b.heap, backend)
-		}
-		backend.mu.Unlock()
+	return hostname, port
+}
+
+// GetNextServer returns the next available healthy backend based on lowest load.
+func (b *AdvancedBalancer) GetNextServer() *BackendImpl {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 
-		return nil, fmt.Errorf("[advanced.backend.Invoke]: failed request: %w", err) // we need to return err for request failed.
+	if b.heap.Len() == 0 {
+		return nil // no healthy backends available.
 	}
-	// if backend succeeded, so we return it to heap.
-	heap.Push(b.heap, backend)
 
-	return resp, err
+	// Select the healthiest backend with lowest load.
+	backend, ok := heap.Pop(b.heap).(*BackendImpl)
+	if !ok {
+		log.Println("heap.Pop(b.heap).(*BackendImpl): type assertion error")
+		return nil
+	}
+
+	return backend
+}
+
+// Invoke sends the request to the next available backend, retrying against
+// the next-best backend on transient failures per b.retry. Marks a backend
+// as unhealthy if it fails repeatedly.
+func (b *AdvancedBalancer) Invoke(ctx context.Context, req Request) (Response, error) {
+	return invokeWithRetry(ctx, b.retry, b.GetNextServer, func(tryCtx context.Context, backend *BackendImpl) (Response, error) {
+		resp, err := backend.Invoke(tryCtx, req)
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if err != nil {
+			backend.mu.Lock() // we need to lock backend's own to safely update failure count.
+			backend.failureCount++
+
+			if backend.failureCount >= maxFailureCount {
+				backend.MarkUnhealthy() // Mark backend as unhealthy after maxFailureCount.
+				backend.mu.Unlock()
+
+				return nil, fmt.Errorf("[advanced.backend.Invoke]: failed request: %w", err)
+			}
+			backend.mu.Unlock()
+
+			// if not maxFailures yet, push back so other requests can still reach it.
+			b.pushIfAbsent(backend)
+
+			return nil, fmt.Errorf("[advanced.backend.Invoke]: failed request: %w", err) // we need to return err for request failed.
+		}
+		// if backend succeeded, so we return it to heap.
+		b.pushIfAbsent(backend)
+
+		return resp, err
+	})
 }
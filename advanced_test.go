@@ -0,0 +1,75 @@
+package golb
+
+import (
+	"fmt"
+	"net/url"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestAdvancedBalancerConcurrentUpsertRemove exercises UpsertServer and
+// RemoveServer from many goroutines at once. Run with -race: it deadlocks if
+// RemoveServer holds b.mu across Prober.Stop, and is also the shape of bug
+// that a naive in-place backend-slice mutation would have raced on.
+func TestAdvancedBalancerConcurrentUpsertRemove(t *testing.T) {
+	b := NewAdvancedLoadBalancerFromBackends(nil)
+	b.SetDrainTimeout(10 * time.Millisecond)
+	defer b.StopHealthChecker()
+
+	const workers = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			u := &url.URL{Host: fmt.Sprintf("127.0.0.1:%d", 20000+i)}
+
+			if err := b.UpsertServer(u); err != nil {
+				t.Errorf("UpsertServer(%s): %v", u.Host, err)
+				return
+			}
+
+			b.Servers()
+
+			if err := b.RemoveServer(u); err != nil {
+				t.Errorf("RemoveServer(%s): %v", u.Host, err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if got := len(b.Servers()); got != 0 {
+		t.Fatalf("expected 0 servers left, got %d", got)
+	}
+}
+
+// TestAdvancedBalancerStopHealthCheckerNoGoroutineLeak verifies that every
+// prober goroutine started for a backend exits once StopHealthChecker
+// returns.
+func TestAdvancedBalancerStopHealthCheckerNoGoroutineLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	urls := []*url.URL{
+		{Host: "127.0.0.1:20100"},
+		{Host: "127.0.0.1:20101"},
+		{Host: "127.0.0.1:20102"},
+	}
+
+	b := NewAdvancedLoadBalancer(urls)
+	b.StopHealthChecker()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := runtime.NumGoroutine(); got > before {
+		t.Fatalf("goroutine leak after StopHealthChecker: before=%d after=%d", before, got)
+	}
+}
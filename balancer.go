@@ -3,17 +3,35 @@ package golb
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 )
 
 const (
 	httpClientTimeOut = 5 * time.Second
 
 	maxFailureCount = 3
+
+	// grpcKeepaliveTime and grpcKeepaliveTimeout configure the keepalive
+	// pings kept on every gRPC backend connection.
+	grpcKeepaliveTime    = 10 * time.Second
+	grpcKeepaliveTimeout = 5 * time.Second
+)
+
+// Protocol identifies how a backend is reached.
+type Protocol string
+
+const (
+	ProtocolHTTP  Protocol = "http"
+	ProtocolHTTPS Protocol = "https"
+	ProtocolGRPC  Protocol = "grpc"
 )
 
 type (
@@ -26,36 +44,136 @@ type Backend interface {
 }
 
 type BackendImpl struct {
-	addr           string       // addr general need for all level.
-	failureCount   int32        // track consecutive failures (for intermediate level).
-	lastFailure    time.Time    // time of last failure (for intermediate level).
-	healthy        int32        // 1 if healthy, 0 if unhealthy (for intermediate level).
-	activeRequests int64        // for tracking current active requests.
-	mu             sync.Mutex   // protects failureCount and lastFailure.
-	client         *http.Client // HTTP client for real requests.
+	addr               string       // addr general need for all level.
+	protocol           Protocol     // http, https, or grpc; defaults to https.
+	failureCount       int32        // track consecutive failures (for intermediate level).
+	lastFailure        time.Time    // time of last failure (for intermediate level).
+	recoveryPolicy     RetryPolicy  // backs IsHealthy's passive recovery wait; scaled by failureCount.
+	passiveRecoveryOff bool         // true once an active health-check prober owns this backend (see disablePassiveRecovery).
+	healthy            int32        // 1 if healthy, 0 if unhealthy (for intermediate level).
+	activeRequests     int64        // for tracking current active requests.
+	mu                 sync.Mutex   // protects failureCount, lastFailure, recoveryPolicy, and passiveRecoveryOff.
+	client             *http.Client // HTTP client for real requests.
+
+	serviceName string           // grpc.health.v1.Health service name (grpc protocol only).
+	grpcConn    *grpc.ClientConn // persistent connection, reused by Invoke and the health prober.
+	healthCli   grpc_health_v1.HealthClient
+
+	metrics       Metrics       // defaults to NoopMetrics.
+	statusUpdater StatusUpdater // optional subscriber to UP/DOWN transitions.
 }
 
 // Ensure BackendImpl implements Backend.
 var _ Backend = (*BackendImpl)(nil)
 
-// NewBackend creates a new BackendImpl.
+// NewBackend creates a new HTTP(S) BackendImpl.
 func NewBackend(addr string) *BackendImpl {
 	return &BackendImpl{
-		addr:    addr,
-		healthy: 1, // start as healthy.
+		addr:           addr,
+		protocol:       ProtocolHTTPS,
+		healthy:        1, // start as healthy.
+		recoveryPolicy: DefaultRetryPolicy(),
 		client: &http.Client{
 			Timeout: httpClientTimeOut,
 		},
+		metrics: NoopMetrics,
+	}
+}
+
+// NewGRPCBackend creates a BackendImpl that speaks the standard
+// grpc.health.v1.Health/Check protocol. It dials addr once and reuses the
+// connection for both Invoke and active health probing.
+func NewGRPCBackend(addr, serviceName string) (*BackendImpl, error) {
+	conn, err := grpc.NewClient(
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                grpcKeepaliveTime,
+			Timeout:             grpcKeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("backend %s: failed to dial grpc target: %w", addr, err)
+	}
+
+	return &BackendImpl{
+		addr:           addr,
+		protocol:       ProtocolGRPC,
+		serviceName:    serviceName,
+		healthy:        1, // start as healthy.
+		recoveryPolicy: DefaultRetryPolicy(),
+		grpcConn:       conn,
+		healthCli:      grpc_health_v1.NewHealthClient(conn),
+		metrics:        NoopMetrics,
+	}, nil
+}
+
+// SetMetrics wires m into the backend; every subsequent Invoke and
+// health-state transition reports through it. The backend's current state
+// is reported immediately so gauges don't start out stale.
+func (b *BackendImpl) SetMetrics(m Metrics) {
+	b.metrics = m
+
+	up := 0.0
+	if atomic.LoadInt32(&b.healthy) == 1 {
+		up = 1
 	}
+	m.BackendUp().With("addr", b.addr).Set(up)
+	m.BackendInflight().With("addr", b.addr).Set(float64(atomic.LoadInt64(&b.activeRequests)))
 }
 
+// SetStatusUpdater registers u to be notified of this backend's UP/DOWN
+// transitions, useful when a balancer's backends are themselves other
+// balancers.
+func (b *BackendImpl) SetStatusUpdater(u StatusUpdater) {
+	b.statusUpdater = u
+}
+
+// Invoke routes the request to either the HTTP client or the gRPC
+// connection, depending on the backend's protocol, and reports the result
+// through b.metrics.
 func (b *BackendImpl) Invoke(ctx context.Context, req Request) (Response, error) {
 	// for advanced level we need to track active requests.
-	atomic.AddInt64(&b.activeRequests, 1)
-	defer atomic.AddInt64(&b.activeRequests, -1)
+	inflight := atomic.AddInt64(&b.activeRequests, 1)
+	b.metrics.BackendInflight().With("addr", b.addr).Set(float64(inflight))
+
+	defer func() {
+		inflight := atomic.AddInt64(&b.activeRequests, -1)
+		b.metrics.BackendInflight().With("addr", b.addr).Set(float64(inflight))
+	}()
+
+	start := time.Now()
+
+	var (
+		resp Response
+		err  error
+	)
+
+	if b.protocol == ProtocolGRPC {
+		resp, err = b.invokeGRPC(ctx)
+	} else {
+		resp, err = b.invokeHTTP(ctx)
+	}
 
-	// create http request.
-	url := fmt.Sprintf("https://%s", b.addr)
+	b.metrics.RequestDuration().With("addr", b.addr).Observe(time.Since(start).Seconds())
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	b.metrics.RequestsTotal().With("addr", b.addr, "result", result).Add(1)
+
+	return resp, err
+}
+
+func (b *BackendImpl) invokeHTTP(ctx context.Context) (Response, error) {
+	scheme := "https"
+	if b.protocol == ProtocolHTTP {
+		scheme = "http"
+	}
+
+	url := fmt.Sprintf("%s://%s", scheme, b.addr)
 
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -78,42 +196,126 @@ func (b *BackendImpl) Invoke(ctx context.Context, req Request) (Response, error)
 			return nil, fmt.Errorf("backend %s failed: %w", b.addr, err)
 		}
 
-		return nil, fmt.Errorf("backend %s returned status %d", b.addr, resp.StatusCode)
+		return nil, &StatusError{Addr: b.addr, Code: resp.StatusCode}
 	}
 	defer resp.Body.Close()
 
 	return fmt.Sprintf("Success from %s", b.addr), nil
 }
 
+// invokeGRPC calls grpc.health.v1.Health/Check on the backend's persistent
+// connection, treating SERVING as success and anything else as failure.
+func (b *BackendImpl) invokeGRPC(ctx context.Context) (Response, error) {
+	resp, err := b.healthCli.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: b.serviceName})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err != nil || resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		b.failureCount++
+		b.lastFailure = time.Now()
+
+		if err != nil {
+			return nil, fmt.Errorf("backend %s grpc check failed: %w", b.addr, err)
+		}
+
+		return nil, fmt.Errorf("backend %s grpc status %s", b.addr, resp.GetStatus())
+	}
+
+	return fmt.Sprintf("Success from %s", b.addr), nil
+}
+
+// Close releases resources held by the backend, such as a persistent gRPC
+// connection. Safe to call on HTTP(S) backends, which hold nothing to close.
+func (b *BackendImpl) Close() error {
+	if b.grpcConn != nil {
+		return b.grpcConn.Close()
+	}
+
+	return nil
+}
+
 // Host returns the backend's address.
 func (b *BackendImpl) Host() string {
 	return b.addr
 }
 
-// IsHealthy checks if the backend is healthy.
+// IsHealthy reports the backend's last known health state. For balancers
+// wired to an active health-check subsystem (see the healthcheck package),
+// that subsystem calls disablePassiveRecovery and owns UP/DOWN transitions
+// outright. Otherwise, a backend marked unhealthy recovers on its own once
+// recoveryPolicy.backoff(failureCount-1) has passed since its last failure —
+// the same exponential backoff and jitter Invoke uses for retries, applied
+// here in place of the baseline's fixed 2*time.Second window.
 func (b *BackendImpl) IsHealthy() bool {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
 	if atomic.LoadInt32(&b.healthy) == 1 {
 		return true
 	}
 
-	// recover backend after some recovery time.
-	if time.Since(b.lastFailure) > 2*time.Second {
-		log.Printf("backend %s recovered", b.addr)
-		atomic.StoreInt32(&b.healthy, 1)
-		b.failureCount = 0
+	b.mu.Lock()
+	off := b.passiveRecoveryOff
+	lastFailure := b.lastFailure
+	attempt := int(b.failureCount) - 1
+	policy := b.recoveryPolicy
+	b.mu.Unlock()
 
-		return true
+	if off {
+		return false
 	}
 
-	return false
+	if attempt < 0 {
+		attempt = 0
+	}
+
+	if time.Since(lastFailure) <= policy.backoff(attempt) {
+		return false
+	}
+
+	b.MarkHealthy()
+
+	return true
+}
+
+// SetRecoveryPolicy overrides the RetryPolicy used to compute IsHealthy's
+// passive recovery wait. Balancers call this with their own RetryPolicy so
+// a backend recovers on the same schedule Invoke retries with.
+func (b *BackendImpl) SetRecoveryPolicy(policy RetryPolicy) {
+	b.mu.Lock()
+	b.recoveryPolicy = policy
+	b.mu.Unlock()
+}
+
+// disablePassiveRecovery turns off IsHealthy's time-based recovery. Used by
+// balancers with an active health-check prober, which already owns this
+// backend's UP/DOWN transitions.
+func (b *BackendImpl) disablePassiveRecovery() {
+	b.mu.Lock()
+	b.passiveRecoveryOff = true
+	b.mu.Unlock()
 }
 
 // MarkUnhealthy marks the backend as unhealthy.
 func (b *BackendImpl) MarkUnhealthy() {
 	atomic.StoreInt32(&b.healthy, 0)
+	b.metrics.BackendUp().With("addr", b.addr).Set(0)
+
+	if b.statusUpdater != nil {
+		b.statusUpdater.OnStatusChange(b.addr, false)
+	}
+}
+
+// MarkHealthy marks the backend as healthy and resets its failure count.
+func (b *BackendImpl) MarkHealthy() {
+	b.mu.Lock()
+	b.failureCount = 0
+	b.mu.Unlock()
+
+	atomic.StoreInt32(&b.healthy, 1)
+	b.metrics.BackendUp().With("addr", b.addr).Set(1)
+
+	if b.statusUpdater != nil {
+		b.statusUpdater.OnStatusChange(b.addr, true)
+	}
 }
 
 // GetLoad returns the current number of active requests.
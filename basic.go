@@ -3,31 +3,45 @@ package golb
 import (
 	"context"
 	"net/url"
+	"sync"
 	"sync/atomic"
 )
 
-// Ensure BasicBalancer implements the Backend interface.
-var _ Backend = (*BasicBalancer)(nil)
+// Ensure BasicBalancer implements the Backend and Balancer interfaces.
+var (
+	_ Backend  = (*BasicBalancer)(nil)
+	_ Balancer = (*BasicBalancer)(nil)
+)
 
 // BasicBalancer uses round-robin to distribute requests across backends.
 type BasicBalancer struct {
+	mu       sync.RWMutex   // protects backends.
 	backends []*BackendImpl // List of backend servers.
 	counter  uint32         // Atomic counter for round-robin selection.
+	retry    RetryPolicy    // retry/backoff policy applied by Invoke.
+	metrics  Metrics        // applied to every backend, including ones added later.
 }
 
 // NewBasicLoadBalancer creates a BasicBalancer from a list of backend URLs.
-func NewBasicLoadBalancer(urls []*url.URL) *BasicBalancer {
+// An optional RetryPolicy overrides DefaultRetryPolicy().
+func NewBasicLoadBalancer(urls []*url.URL, policy ...RetryPolicy) *BasicBalancer {
+	retry := resolveRetryPolicy(policy)
+
 	backends := make([]*BackendImpl, len(urls))
 	for i, u := range urls {
 		backends[i] = NewBackend(u.Host)
+		backends[i].SetRecoveryPolicy(retry)
 	}
 
-	return &BasicBalancer{backends: backends}
+	return &BasicBalancer{backends: backends, retry: retry, metrics: NoopMetrics}
 }
 
 // GetNextServer selects the next backend using round-robin.
 // Does not check for health; always returns the next backend.
 func (b *BasicBalancer) GetNextServer() *BackendImpl {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	if len(b.backends) == 0 {
 		return nil // no servers available.
 	}
@@ -37,12 +51,80 @@ func (b *BasicBalancer) GetNextServer() *BackendImpl {
 	return b.backends[idx]
 }
 
-// Invoke sends the request to the next backend.
+// Invoke sends the request to the next backend, retrying against the
+// next-best backend on transient failures per b.retry.
 func (b *BasicBalancer) Invoke(ctx context.Context, req Request) (Response, error) {
-	backend := b.GetNextServer()
-	if backend == nil {
-		return nil, ErrBackendServersEmpty
+	return invokeWithRetry(ctx, b.retry, b.GetNextServer, func(tryCtx context.Context, backend *BackendImpl) (Response, error) {
+		return backend.Invoke(tryCtx, req)
+	})
+}
+
+// SetMetrics wires m into every backend, including ones added afterwards.
+func (b *BasicBalancer) SetMetrics(m Metrics) {
+	b.mu.Lock()
+	b.metrics = m
+	backends := append([]*BackendImpl(nil), b.backends...)
+	b.mu.Unlock()
+
+	for _, backend := range backends {
+		backend.SetMetrics(m)
+	}
+}
+
+// UpsertServer adds u if it is not already present. u.Host already present
+// is a no-op: BasicBalancer has no per-backend options to update.
+func (b *BasicBalancer) UpsertServer(u *url.URL, _ ...ServerOption) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, backend := range b.backends {
+		if backend.addr == u.Host {
+			return nil
+		}
+	}
+
+	backend := NewBackend(u.Host)
+	backend.SetMetrics(b.metrics)
+	backend.SetRecoveryPolicy(b.retry)
+	b.backends = append(b.backends, backend)
+
+	return nil
+}
+
+// RemoveServer removes the backend for u, if present.
+func (b *BasicBalancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	idx := -1
+	for i, backend := range b.backends {
+		if backend.addr == u.Host {
+			idx = i
+			break
+		}
+	}
+
+	if idx == -1 {
+		return ErrBackendNotFound
+	}
+
+	updated := make([]*BackendImpl, 0, len(b.backends)-1)
+	updated = append(updated, b.backends[:idx]...)
+	updated = append(updated, b.backends[idx+1:]...)
+	b.backends = updated
+
+	return nil
+}
+
+// Servers lists the currently registered backend URLs.
+func (b *BasicBalancer) Servers() []*url.URL {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	urls := make([]*url.URL, len(b.backends))
+	for i, backend := range b.backends {
+		urls[i] = &url.URL{Host: backend.addr}
 	}
 
-	return backend.Invoke(ctx, req)
+	return urls
 }
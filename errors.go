@@ -1,9 +1,28 @@
 package golb
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrBackendServersEmpty is returned when backend list is empty.
 var ErrBackendServersEmpty = errors.New("backend server list is empty")
 
 // ErrNoAvailableBackends is returned when no backends are available.
 var ErrNoAvailableBackends = errors.New("no available backends")
+
+// ErrBackendNotFound is returned by RemoveServer when no backend matches
+// the given URL.
+var ErrBackendNotFound = errors.New("backend not found")
+
+// StatusError is returned by BackendImpl.Invoke when a backend responds
+// with a non-2xx HTTP status, so retry policies can inspect the status
+// code via errors.As instead of parsing the error string.
+type StatusError struct {
+	Addr string
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("backend %s returned status %d", e.Addr, e.Code)
+}
@@ -13,7 +13,7 @@ import (
 
 func main() {
 	var level string
-	flag.StringVar(&level, "level", "basic", "there are 3 levels for lb: basic, intermediate, advanced")
+	flag.StringVar(&level, "level", "basic", "there are 4 levels for lb: basic, intermediate, advanced, weighted")
 	flag.Parse()
 
 	urls := []*url.URL{
@@ -68,5 +68,9 @@ func main() {
 		}
 
 		b.StopHealthChecker() // stop the health checker before exiting.
+	case *golb.WeightedBalancer:
+		for i := 0; i < 6; i++ {
+			fmt.Println(b.GetNextServer().Host())
+		}
 	}
 }
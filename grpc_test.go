@@ -0,0 +1,47 @@
+package golb
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// TestGRPCBackendInvokeFollowsServingStatus verifies Invoke on a gRPC
+// backend succeeds while the standard grpc.health.v1.Health service reports
+// SERVING, and fails once it reports anything else.
+func TestGRPCBackendInvokeFollowsServingStatus(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer lis.Close()
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("svc", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	srv := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(srv, healthSrv)
+
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	backend, err := NewGRPCBackend(lis.Addr().String(), "svc")
+	if err != nil {
+		t.Fatalf("NewGRPCBackend: %v", err)
+	}
+	defer backend.Close()
+
+	if _, err := backend.Invoke(context.Background(), nil); err != nil {
+		t.Fatalf("Invoke while SERVING: %v", err)
+	}
+
+	healthSrv.SetServingStatus("svc", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	if _, err := backend.Invoke(context.Background(), nil); err == nil {
+		t.Fatal("Invoke while NOT_SERVING: expected error, got nil")
+	}
+}
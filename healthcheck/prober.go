@@ -0,0 +1,309 @@
+// Package healthcheck implements active, out-of-band health probing for
+// backend servers, modeled after Traefik's healthcheck package.
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	defaultInterval  = 10 * time.Second
+	defaultTimeout   = 5 * time.Second
+	defaultRiseCount = 1
+	defaultFallCount = 1
+)
+
+// Options configures a Prober.
+type Options struct {
+	Protocol        string            // "http", "https", or "grpc"; defaults to http-style HTTP probing.
+	Scheme          string            // http or https, defaults to http.
+	Hostname        string            // target hostname or IP.
+	Port            string            // target port, optional.
+	Path            string            // request path, defaults to "/".
+	Method          string            // HTTP method, defaults to GET.
+	Headers         map[string]string // extra headers sent with every probe.
+	Interval        time.Duration     // time between probes, defaults to 10s.
+	Timeout         time.Duration     // per-probe timeout, defaults to 5s.
+	FollowRedirects bool              // whether the probe client follows redirects.
+
+	// ExpectedStatuses lists status codes considered healthy.
+	// An empty slice means "any 2xx".
+	ExpectedStatuses []int
+
+	// RiseCount is the number of consecutive successes required before a
+	// backend transitions from DOWN to UP. Defaults to 1.
+	RiseCount int
+	// FallCount is the number of consecutive failures required before a
+	// backend transitions from UP to DOWN. Defaults to 1.
+	FallCount int
+
+	// GRPCConn is the backend's persistent connection, owned by the caller,
+	// used when Protocol is "grpc". The prober never dials or closes it.
+	GRPCConn *grpc.ClientConn
+	// ServiceName is the grpc.health.v1.Health service name to check.
+	ServiceName string
+}
+
+// withDefaults returns a copy of o with zero-value fields filled in.
+func (o Options) withDefaults() Options {
+	if o.Scheme == "" {
+		o.Scheme = "http"
+	}
+
+	if o.Path == "" {
+		o.Path = "/"
+	}
+
+	if o.Method == "" {
+		o.Method = http.MethodGet
+	}
+
+	if o.Interval <= 0 {
+		o.Interval = defaultInterval
+	}
+
+	if o.Timeout <= 0 {
+		o.Timeout = defaultTimeout
+	}
+
+	if o.RiseCount <= 0 {
+		o.RiseCount = defaultRiseCount
+	}
+
+	if o.FallCount <= 0 {
+		o.FallCount = defaultFallCount
+	}
+
+	return o
+}
+
+// url builds the probe target URL from the options.
+func (o Options) url() string {
+	host := o.Hostname
+	if o.Port != "" {
+		host = net.JoinHostPort(o.Hostname, o.Port)
+	}
+
+	return fmt.Sprintf("%s://%s%s", o.Scheme, host, o.Path)
+}
+
+// statusOK reports whether statusCode counts as a healthy response.
+func (o Options) statusOK(statusCode int) bool {
+	if len(o.ExpectedStatuses) == 0 {
+		return statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+	}
+
+	for _, s := range o.ExpectedStatuses {
+		if s == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Prober actively probes a single backend on its own goroutine, issuing real
+// requests and transitioning UP/DOWN only after enough consecutive results.
+type Prober struct {
+	opts     Options
+	client   *http.Client
+	onChange func(up bool)
+
+	mu        sync.Mutex
+	up        bool
+	successes int
+	failures  int
+
+	stopChan     chan struct{}
+	doneChan     chan struct{}
+	intervalChan chan time.Duration
+}
+
+// NewProber creates a Prober that calls onChange whenever the backend's
+// health state flips. onChange is invoked from the prober's own goroutine.
+func NewProber(opts Options, onChange func(up bool)) *Prober {
+	opts = opts.withDefaults()
+
+	client := &http.Client{Timeout: opts.Timeout}
+	if !opts.FollowRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	return &Prober{
+		opts:         opts,
+		client:       client,
+		onChange:     onChange,
+		stopChan:     make(chan struct{}),
+		doneChan:     make(chan struct{}),
+		intervalChan: make(chan time.Duration, 1),
+	}
+}
+
+// Start launches the probe loop. The first probe fires immediately rather
+// than waiting for the first tick.
+func (p *Prober) Start() {
+	go p.run()
+}
+
+// Stop stops the probe loop and waits for it to exit.
+func (p *Prober) Stop() {
+	close(p.stopChan)
+	<-p.doneChan
+}
+
+func (p *Prober) run() {
+	defer close(p.doneChan)
+
+	p.probe()
+
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.probe()
+		case d := <-p.intervalChan:
+			p.mu.Lock()
+			p.opts.Interval = d
+			p.mu.Unlock()
+			ticker.Reset(d)
+		case <-p.stopChan:
+			return
+		}
+	}
+}
+
+// SetInterval changes the probe interval without restarting the probe loop.
+// It is safe to call from the onChange callback itself. If an interval
+// change is already pending, the new value replaces it.
+func (p *Prober) SetInterval(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	// Discard any pending value so the latest interval wins.
+	select {
+	case <-p.intervalChan:
+	default:
+	}
+
+	select {
+	case p.intervalChan <- d:
+	default:
+	}
+}
+
+// probe issues a single request and updates the consecutive success/failure
+// counters, firing onChange when a transition threshold is crossed.
+func (p *Prober) probe() {
+	ok := p.check()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ok {
+		p.failures = 0
+		p.successes++
+
+		if !p.up && p.successes >= p.opts.RiseCount {
+			p.up = true
+			p.onChange(true)
+		}
+
+		return
+	}
+
+	p.successes = 0
+	p.failures++
+
+	if p.up && p.failures >= p.opts.FallCount {
+		p.up = false
+		p.onChange(false)
+	}
+}
+
+// check performs one probe and reports whether the backend counts as
+// healthy, dispatching to HTTP or gRPC depending on the configured protocol.
+func (p *Prober) check() bool {
+	if p.opts.Protocol == "grpc" {
+		return p.checkGRPC()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, p.opts.Method, p.opts.url(), nil)
+	if err != nil {
+		return false
+	}
+
+	for k, v := range p.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return p.opts.statusOK(resp.StatusCode)
+}
+
+// checkGRPC invokes grpc.health.v1.Health/Check on the backend's persistent
+// connection and reports whether the service reported SERVING.
+func (p *Prober) checkGRPC() bool {
+	if p.opts.GRPCConn == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.Timeout)
+	defer cancel()
+
+	client := grpc_health_v1.NewHealthClient(p.opts.GRPCConn)
+
+	resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: p.opts.ServiceName})
+	if err != nil {
+		return false
+	}
+
+	return resp.GetStatus() == grpc_health_v1.HealthCheckResponse_SERVING
+}
+
+// Reconfigure swaps in new options, restarting the probe loop so the change
+// takes effect without losing the current running state.
+func (p *Prober) Reconfigure(opts Options) {
+	p.Stop()
+
+	opts = opts.withDefaults()
+
+	p.mu.Lock()
+	p.opts = opts
+	p.successes = 0
+	p.failures = 0
+	p.mu.Unlock()
+
+	client := &http.Client{Timeout: opts.Timeout}
+	if !opts.FollowRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	p.client = client
+
+	p.stopChan = make(chan struct{})
+	p.doneChan = make(chan struct{})
+
+	p.Start()
+}
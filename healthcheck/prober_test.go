@@ -0,0 +1,116 @@
+package healthcheck
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestProberRiseFallCounts verifies a state flip requires RiseCount
+// consecutive successes (or FallCount consecutive failures), not just one.
+func TestProberRiseFallCounts(t *testing.T) {
+	healthy := true
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if healthy {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("parse server URL: %v", err)
+	}
+
+	hostname, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		t.Fatalf("split host port: %v", err)
+	}
+
+	var transitions []bool
+	p := NewProber(Options{
+		Scheme:    "http",
+		Hostname:  hostname,
+		Port:      port,
+		Path:      "/",
+		Timeout:   time.Second,
+		RiseCount: 2,
+		FallCount: 2,
+	}, func(up bool) {
+		transitions = append(transitions, up)
+	})
+
+	p.probe()
+	if len(transitions) != 0 {
+		t.Fatalf("onChange fired after 1 success, want 0 transitions, got %v", transitions)
+	}
+
+	p.probe()
+	if len(transitions) != 1 || !transitions[0] {
+		t.Fatalf("after 2 successes, want [true], got %v", transitions)
+	}
+
+	healthy = false
+
+	p.probe()
+	if len(transitions) != 1 {
+		t.Fatalf("onChange fired after 1 failure, want still 1 transition, got %v", transitions)
+	}
+
+	p.probe()
+	if len(transitions) != 2 || transitions[1] {
+		t.Fatalf("after 2 failures, want [true false], got %v", transitions)
+	}
+}
+
+// TestProberStartProbesImmediately verifies Start fires the first probe
+// right away instead of waiting for the first interval tick.
+func TestProberStartProbesImmediately(t *testing.T) {
+	var hits int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("parse server URL: %v", err)
+	}
+
+	hostname, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		t.Fatalf("split host port: %v", err)
+	}
+
+	p := NewProber(Options{
+		Scheme:   "http",
+		Hostname: hostname,
+		Port:     port,
+		Path:     "/",
+		Timeout:  time.Second,
+		// Long enough that only the immediate first probe could complete
+		// within the test's deadline below.
+		Interval: time.Hour,
+	}, func(up bool) {})
+
+	p.Start()
+	defer p.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&hits) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if atomic.LoadInt32(&hits) == 0 {
+		t.Fatal("Start did not issue an immediate probe")
+	}
+}
@@ -3,32 +3,47 @@ package golb
 import (
 	"context"
 	"net/url"
+	"sync"
 	"sync/atomic"
 )
 
-// Ensure IntermediateBalancer implements the Backend interface.
-var _ Backend = (*IntermediateBalancer)(nil)
+// Ensure IntermediateBalancer implements the Backend and Balancer interfaces.
+var (
+	_ Backend  = (*IntermediateBalancer)(nil)
+	_ Balancer = (*IntermediateBalancer)(nil)
+)
 
 // IntermediateBalancer adds failure tracking and re-inclusion.
 type IntermediateBalancer struct {
+	mu       sync.RWMutex   // protects backends.
 	backends []*BackendImpl // List of all backends.
 	counter  uint32         // Atomic counter for round-robin index tracking.
+	retry    RetryPolicy    // retry/backoff policy applied by Invoke.
+	metrics  Metrics        // applied to every backend, including ones added later.
 }
 
 // NewIntermediateLoadBalancer creates and returns a new initialied IntermediateBalancer.
-func NewIntermediateLoadBalancer(urls []*url.URL) *IntermediateBalancer {
+// An optional RetryPolicy overrides DefaultRetryPolicy().
+func NewIntermediateLoadBalancer(urls []*url.URL, policy ...RetryPolicy) *IntermediateBalancer {
+	retry := resolveRetryPolicy(policy)
+
 	backends := make([]*BackendImpl, len(urls))
 	for i, u := range urls {
 		backends[i] = NewBackend(u.Host)
+		backends[i].SetRecoveryPolicy(retry)
 	}
 
-	return &IntermediateBalancer{backends: backends}
+	return &IntermediateBalancer{backends: backends, retry: retry, metrics: NoopMetrics}
 }
 
 // GetNextServer selects the next healthy backend using round-robin strategy.
 // Skips unhealthy backends, tries at most once per backend.
 func (b *IntermediateBalancer) GetNextServer() *BackendImpl {
-	n := len(b.backends)
+	b.mu.RLock()
+	backends := b.backends
+	b.mu.RUnlock()
+
+	n := len(backends)
 	if n == 0 {
 		return nil
 	}
@@ -36,7 +51,7 @@ func (b *IntermediateBalancer) GetNextServer() *BackendImpl {
 	for i := 0; i < n; i++ {
 		// Atomically get the next index in round-robin fashion.
 		idx := int(atomic.AddUint32(&b.counter, 1)-1) % n
-		backend := b.backends[idx]
+		backend := backends[idx]
 
 		if backend.IsHealthy() {
 			return backend
@@ -46,25 +61,92 @@ func (b *IntermediateBalancer) GetNextServer() *BackendImpl {
 	return nil // No healthy backends found.
 }
 
-// Invoke sends the request to a healthy backend.
-// If the backend fails repeatedly, it's marked unhealthy.
+// Invoke sends the request to a healthy backend, retrying against the
+// next-best backend on transient failures per b.retry.
+// If a backend fails repeatedly, it's marked unhealthy.
 func (b *IntermediateBalancer) Invoke(ctx context.Context, req Request) (Response, error) {
-	backend := b.GetNextServer()
-	if backend == nil {
-		return nil, ErrBackendServersEmpty
+	return invokeWithRetry(ctx, b.retry, b.GetNextServer, func(tryCtx context.Context, backend *BackendImpl) (Response, error) {
+		resp, err := backend.Invoke(tryCtx, req)
+		if err != nil {
+			// Check failure count and mark unhealthy if maxFailure reached.
+			backend.mu.Lock()
+			if backend.failureCount >= maxFailureCount { // Max 3 consecutive failures.
+				backend.MarkUnhealthy()
+			}
+			backend.mu.Unlock()
+
+			return nil, err
+		}
+
+		return resp, nil
+	})
+}
+
+// SetMetrics wires m into every backend, including ones added afterwards.
+func (b *IntermediateBalancer) SetMetrics(m Metrics) {
+	b.mu.Lock()
+	b.metrics = m
+	backends := append([]*BackendImpl(nil), b.backends...)
+	b.mu.Unlock()
+
+	for _, backend := range backends {
+		backend.SetMetrics(m)
 	}
+}
 
-	resp, err := backend.Invoke(ctx, req)
-	if err != nil {
-		// Check failure count and mark unhealthy if maxFailure reached.
-		backend.mu.Lock()
-		if backend.failureCount >= maxFailureCount { // Max 3 consecutive failures.
-			backend.MarkUnhealthy()
+// UpsertServer adds u if it is not already present.
+func (b *IntermediateBalancer) UpsertServer(u *url.URL, _ ...ServerOption) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, backend := range b.backends {
+		if backend.addr == u.Host {
+			return nil
 		}
-		backend.mu.Unlock()
+	}
+
+	backend := NewBackend(u.Host)
+	backend.SetMetrics(b.metrics)
+	backend.SetRecoveryPolicy(b.retry)
+	b.backends = append(b.backends, backend)
+
+	return nil
+}
+
+// RemoveServer removes the backend for u, if present.
+func (b *IntermediateBalancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	idx := -1
+	for i, backend := range b.backends {
+		if backend.addr == u.Host {
+			idx = i
+			break
+		}
+	}
+
+	if idx == -1 {
+		return ErrBackendNotFound
+	}
+
+	updated := make([]*BackendImpl, 0, len(b.backends)-1)
+	updated = append(updated, b.backends[:idx]...)
+	updated = append(updated, b.backends[idx+1:]...)
+	b.backends = updated
+
+	return nil
+}
+
+// Servers lists the currently registered backend URLs.
+func (b *IntermediateBalancer) Servers() []*url.URL {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
 
-		return nil, err
+	urls := make([]*url.URL, len(b.backends))
+	for i, backend := range b.backends {
+		urls[i] = &url.URL{Host: backend.addr}
 	}
 
-	return resp, nil
+	return urls
 }
@@ -10,7 +10,21 @@ func NewBalancer(level string, urls []*url.URL) Backend {
 		return NewIntermediateLoadBalancer(urls)
 	case "advanced":
 		return NewAdvancedLoadBalancer(urls)
+	case "weighted":
+		return NewWeightedLoadBalancer(equalWeights(urls))
 	}
 
 	return nil
 }
+
+// equalWeights assigns every URL the same weight of 1, for callers that
+// reach the WeightedBalancer through NewBalancer's untyped URL list instead
+// of constructing it directly with per-backend weights.
+func equalWeights(urls []*url.URL) []WeightedURL {
+	weighted := make([]WeightedURL, len(urls))
+	for i, u := range urls {
+		weighted[i] = WeightedURL{URL: u, Weight: 1}
+	}
+
+	return weighted
+}
@@ -0,0 +1,46 @@
+package golb
+
+import "net/url"
+
+// Balancer extends Backend with the ability to add, remove, and list
+// backends at runtime, instead of only accepting a static list at
+// construction.
+type Balancer interface {
+	Backend
+
+	// UpsertServer adds u if it is not already present. Calling it again
+	// for an address that already exists is a no-op, except for options
+	// (such as WithWeight) that a balancer supports updating in place.
+	UpsertServer(u *url.URL, opts ...ServerOption) error
+	// RemoveServer removes u, if present.
+	RemoveServer(u *url.URL) error
+	// Servers lists the currently registered backend URLs.
+	Servers() []*url.URL
+}
+
+// serverConfig collects the options passed to UpsertServer.
+type serverConfig struct {
+	weight float64
+}
+
+// ServerOption customizes a backend passed to Balancer.UpsertServer.
+type ServerOption func(*serverConfig)
+
+// WithWeight sets the backend's distribution weight, for balancers that
+// support weighted scheduling (currently WeightedBalancer). Balancers
+// without a weighting concept ignore it.
+func WithWeight(weight float64) ServerOption {
+	return func(c *serverConfig) {
+		c.weight = weight
+	}
+}
+
+// resolveServerConfig applies opts over a zero-valued serverConfig.
+func resolveServerConfig(opts []ServerOption) serverConfig {
+	var cfg serverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
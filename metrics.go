@@ -0,0 +1,72 @@
+package golb
+
+// Gauge tracks a value that can go up or down, such as the number of
+// in-flight requests. Modeled after go-kit's metrics.Gauge.
+type Gauge interface {
+	With(labelValues ...string) Gauge
+	Set(value float64)
+	Add(delta float64)
+}
+
+// Counter tracks a monotonically increasing value, such as total requests
+// served. Modeled after go-kit's metrics.Counter.
+type Counter interface {
+	With(labelValues ...string) Counter
+	Add(delta float64)
+}
+
+// Histogram tracks the distribution of a value, such as request latency.
+// Modeled after go-kit's metrics.Histogram.
+type Histogram interface {
+	With(labelValues ...string) Histogram
+	Observe(value float64)
+}
+
+// Metrics is the observability surface threaded through BackendImpl and the
+// balancers. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// BackendUp returns the backend_up{addr} gauge, 1 when healthy, 0 when not.
+	BackendUp() Gauge
+	// BackendInflight returns the backend_inflight{addr} gauge.
+	BackendInflight() Gauge
+	// RequestsTotal returns the backend_requests_total{addr,result} counter.
+	RequestsTotal() Counter
+	// RequestDuration returns the backend_request_duration_seconds{addr} histogram.
+	RequestDuration() Histogram
+}
+
+// StatusUpdater lets a parent component subscribe to a backend's UP/DOWN
+// transitions, useful when balancers are nested (e.g. an AdvancedBalancer
+// whose backends are themselves other balancers).
+type StatusUpdater interface {
+	OnStatusChange(addr string, up bool)
+}
+
+// noopMetrics is the default Metrics implementation: every method is a
+// cheap no-op, so instrumentation can stay unconditional in hot paths.
+type noopMetrics struct{}
+
+// NoopMetrics is the Metrics implementation used until a caller supplies
+// its own via SetMetrics.
+var NoopMetrics Metrics = noopMetrics{}
+
+func (noopMetrics) BackendUp() Gauge           { return noopGauge{} }
+func (noopMetrics) BackendInflight() Gauge     { return noopGauge{} }
+func (noopMetrics) RequestsTotal() Counter     { return noopCounter{} }
+func (noopMetrics) RequestDuration() Histogram { return noopHistogram{} }
+
+type noopGauge struct{}
+
+func (noopGauge) With(...string) Gauge { return noopGauge{} }
+func (noopGauge) Set(float64)          {}
+func (noopGauge) Add(float64)          {}
+
+type noopCounter struct{}
+
+func (noopCounter) With(...string) Counter { return noopCounter{} }
+func (noopCounter) Add(float64)            {}
+
+type noopHistogram struct{}
+
+func (noopHistogram) With(...string) Histogram { return noopHistogram{} }
+func (noopHistogram) Observe(float64)          {}
@@ -0,0 +1,115 @@
+// Package prometheus adapts golb.Metrics to the Prometheus client library.
+package prometheus
+
+import (
+	"github.com/jumayevgadam/golb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements golb.Metrics backed by Prometheus vectors.
+type Metrics struct {
+	backendUp       *gaugeAdapter
+	backendInflight *gaugeAdapter
+	requestsTotal   *counterAdapter
+	requestDuration *histogramAdapter
+}
+
+// Ensure Metrics implements golb.Metrics.
+var _ golb.Metrics = (*Metrics)(nil)
+
+// New creates the backend_up, backend_inflight, backend_requests_total, and
+// backend_request_duration_seconds vectors and registers them with reg.
+func New(reg prometheus.Registerer) *Metrics {
+	backendUp := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "backend_up",
+		Help: "Whether a backend is currently healthy (1) or not (0).",
+	}, []string{"addr"})
+
+	backendInflight := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "backend_inflight",
+		Help: "Number of requests currently in flight to a backend.",
+	}, []string{"addr"})
+
+	requestsTotal := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "backend_requests_total",
+		Help: "Total requests sent to a backend, labeled by result.",
+	}, []string{"addr", "result"})
+
+	requestDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "backend_request_duration_seconds",
+		Help:    "Latency of requests sent to a backend.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"addr"})
+
+	reg.MustRegister(backendUp, backendInflight, requestsTotal, requestDuration)
+
+	return &Metrics{
+		backendUp:       &gaugeAdapter{vec: backendUp},
+		backendInflight: &gaugeAdapter{vec: backendInflight},
+		requestsTotal:   &counterAdapter{vec: requestsTotal},
+		requestDuration: &histogramAdapter{vec: requestDuration},
+	}
+}
+
+func (m *Metrics) BackendUp() golb.Gauge           { return m.backendUp }
+func (m *Metrics) BackendInflight() golb.Gauge     { return m.backendInflight }
+func (m *Metrics) RequestsTotal() golb.Counter     { return m.requestsTotal }
+func (m *Metrics) RequestDuration() golb.Histogram { return m.requestDuration }
+
+// labelPairs converts go-kit style alternating label name/value pairs (as
+// passed to With) into a prometheus.Labels map, so labels are matched by
+// name rather than by position. A trailing name with no value is dropped.
+func labelPairs(labelValues []string) prometheus.Labels {
+	labels := make(prometheus.Labels, len(labelValues)/2)
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		labels[labelValues[i]] = labelValues[i+1]
+	}
+
+	return labels
+}
+
+// gaugeAdapter adapts a *prometheus.GaugeVec to golb.Gauge.
+type gaugeAdapter struct {
+	vec    *prometheus.GaugeVec
+	labels prometheus.Labels
+}
+
+func (g *gaugeAdapter) With(labelValues ...string) golb.Gauge {
+	return &gaugeAdapter{vec: g.vec, labels: labelPairs(labelValues)}
+}
+
+func (g *gaugeAdapter) Set(value float64) {
+	g.vec.With(g.labels).Set(value)
+}
+
+func (g *gaugeAdapter) Add(delta float64) {
+	g.vec.With(g.labels).Add(delta)
+}
+
+// counterAdapter adapts a *prometheus.CounterVec to golb.Counter.
+type counterAdapter struct {
+	vec    *prometheus.CounterVec
+	labels prometheus.Labels
+}
+
+func (c *counterAdapter) With(labelValues ...string) golb.Counter {
+	return &counterAdapter{vec: c.vec, labels: labelPairs(labelValues)}
+}
+
+func (c *counterAdapter) Add(delta float64) {
+	c.vec.With(c.labels).Add(delta)
+}
+
+// histogramAdapter adapts a *prometheus.HistogramVec to golb.Histogram.
+type histogramAdapter struct {
+	vec    *prometheus.HistogramVec
+	labels prometheus.Labels
+}
+
+func (h *histogramAdapter) With(labelValues ...string) golb.Histogram {
+	return &histogramAdapter{vec: h.vec, labels: labelPairs(labelValues)}
+}
+
+func (h *histogramAdapter) Observe(value float64) {
+	h.vec.With(h.labels).Observe(value)
+}
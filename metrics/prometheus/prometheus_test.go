@@ -0,0 +1,36 @@
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestMetricsWithMatchesLabelsByName exercises the exact call shape
+// balancer.go uses (e.g. RequestsTotal().With("addr", addr, "result", result)):
+// go-kit style alternating name/value pairs, not positional WithLabelValues
+// arguments. It previously panicked with "inconsistent label cardinality".
+func TestMetricsWithMatchesLabelsByName(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := New(reg)
+
+	const addr = "127.0.0.1:8080"
+
+	m.BackendUp().With("addr", addr).Set(1)
+	m.BackendInflight().With("addr", addr).Set(3)
+	m.RequestsTotal().With("addr", addr, "result", "success").Add(1)
+	m.RequestDuration().With("addr", addr).Observe(0.25)
+
+	if got := testutil.ToFloat64(m.backendUp.vec.WithLabelValues(addr)); got != 1 {
+		t.Fatalf("backend_up{addr=%q} = %v, want 1", addr, got)
+	}
+
+	if got := testutil.ToFloat64(m.backendInflight.vec.WithLabelValues(addr)); got != 3 {
+		t.Fatalf("backend_inflight{addr=%q} = %v, want 3", addr, got)
+	}
+
+	if got := testutil.ToFloat64(m.requestsTotal.vec.WithLabelValues(addr, "success")); got != 1 {
+		t.Fatalf("backend_requests_total{addr=%q,result=success} = %v, want 1", addr, got)
+	}
+}
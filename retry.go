@@ -0,0 +1,183 @@
+package golb
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 1 * time.Second
+	defaultFactor      = 1.6
+	defaultJitter      = 0.2
+	defaultMaxDelay    = 120 * time.Second
+)
+
+// RetryPolicy configures how a balancer retries Invoke against the
+// next-best backend after a transient failure. The backoff is modeled
+// after gRPC's BackoffConfig: nextDelay = min(baseDelay*factor^attempt, maxDelay),
+// then delay *= 1 + jitter*(rand*2-1).
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts, including the first. Defaults to 3.
+	BaseDelay   time.Duration // delay before the first retry. Defaults to 1s.
+	Factor      float64       // multiplier applied per attempt. Defaults to 1.6.
+	Jitter      float64       // multiplicative jitter fraction. 0.2 in DefaultRetryPolicy; zero means no jitter.
+	MaxDelay    time.Duration // upper bound on the computed delay. Defaults to 120s.
+
+	// RetryableStatuses lists HTTP status codes worth retrying. An empty
+	// slice means "retry every error", including statuses wrapped in a
+	// *StatusError and transport-level errors that carry no status.
+	RetryableStatuses []int
+
+	// PerTryTimeout bounds a single attempt. Zero means the caller's
+	// context deadline (if any) is used as-is.
+	PerTryTimeout time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used by balancer constructors when
+// the caller does not supply one.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultMaxAttempts,
+		BaseDelay:   defaultBaseDelay,
+		Factor:      defaultFactor,
+		Jitter:      defaultJitter,
+		MaxDelay:    defaultMaxDelay,
+	}
+}
+
+// resolveRetryPolicy returns policy[0] if the caller provided one, falling
+// back to DefaultRetryPolicy() otherwise, and backfills zero-valued
+// MaxAttempts, BaseDelay, Factor, and MaxDelay so partially-specified
+// policies still behave sensibly. A zero Jitter is kept as-is.
+func resolveRetryPolicy(policy []RetryPolicy) RetryPolicy {
+	if len(policy) == 0 {
+		return DefaultRetryPolicy()
+	}
+
+	p := policy[0]
+	defaults := DefaultRetryPolicy()
+
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+
+	if p.Factor <= 0 {
+		p.Factor = defaults.Factor
+	}
+
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+
+	return p
+}
+
+// backoff computes the delay before the (0-indexed) retry attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(p.Factor, float64(attempt))
+	if maxDelay := float64(p.MaxDelay); delay > maxDelay {
+		delay = maxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(rand.Float64()*2-1)
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+
+	return time.Duration(delay)
+}
+
+// retryable reports whether err should trigger another attempt.
+func (p RetryPolicy) retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if len(p.RetryableStatuses) == 0 {
+		return true
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		for _, s := range p.RetryableStatuses {
+			if s == statusErr.Code {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// transport-level errors carry no status; always worth retrying.
+	return true
+}
+
+// invokeWithRetry calls invoke against backends returned by next, retrying
+// on transient failures with exponential backoff and jitter between
+// attempts. It stops early on success, a non-retryable error, exhausted
+// attempts, or context cancellation.
+func invokeWithRetry(
+	ctx context.Context,
+	policy RetryPolicy,
+	next func() *BackendImpl,
+	invoke func(context.Context, *BackendImpl) (Response, error),
+) (Response, error) {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		backend := next()
+		if backend == nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+
+			return nil, ErrBackendServersEmpty
+		}
+
+		tryCtx := ctx
+
+		var cancel context.CancelFunc
+		if policy.PerTryTimeout > 0 {
+			tryCtx, cancel = context.WithTimeout(ctx, policy.PerTryTimeout)
+		}
+
+		resp, err := invoke(tryCtx, backend)
+		if cancel != nil {
+			cancel()
+		}
+
+		if err == nil {
+			return resp, nil
+		}
+
+		lastErr = err
+
+		if !policy.retryable(err) || attempt == maxAttempts-1 {
+			return nil, lastErr
+		}
+
+		select {
+		case <-time.After(policy.backoff(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, lastErr
+}
@@ -0,0 +1,72 @@
+package golb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInvokeWithRetryStopsOnSuccess(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Factor: 1.5, MaxDelay: time.Second}
+
+	attempts := 0
+	resp, err := invokeWithRetry(context.Background(), policy,
+		func() *BackendImpl { return NewBackend("irrelevant") },
+		func(ctx context.Context, backend *BackendImpl) (Response, error) {
+			attempts++
+			if attempts < 2 {
+				return nil, errors.New("transient")
+			}
+
+			return "ok", nil
+		})
+	if err != nil {
+		t.Fatalf("invokeWithRetry: %v", err)
+	}
+
+	if resp != "ok" {
+		t.Fatalf("resp = %v, want ok", resp)
+	}
+
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestInvokeWithRetryStopsOnNonRetryableStatus(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:       3,
+		BaseDelay:         time.Millisecond,
+		Factor:            1.5,
+		MaxDelay:          time.Second,
+		RetryableStatuses: []int{500},
+	}
+
+	attempts := 0
+	_, err := invokeWithRetry(context.Background(), policy,
+		func() *BackendImpl { return NewBackend("irrelevant") },
+		func(ctx context.Context, backend *BackendImpl) (Response, error) {
+			attempts++
+			return nil, &StatusError{Addr: "irrelevant", Code: 404}
+		})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1 (404 not in RetryableStatuses)", attempts)
+	}
+}
+
+func TestRetryPolicyBackoffRespectsMaxDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, Factor: 2, MaxDelay: 3 * time.Second}
+
+	if got := policy.backoff(0); got != time.Second {
+		t.Fatalf("backoff(0) = %v, want 1s", got)
+	}
+
+	if got := policy.backoff(5); got != 3*time.Second {
+		t.Fatalf("backoff(5) = %v, want capped at 3s", got)
+	}
+}
@@ -0,0 +1,40 @@
+package golb
+
+import "testing"
+
+type recordingStatusUpdater struct {
+	events []statusEvent
+}
+
+type statusEvent struct {
+	addr string
+	up   bool
+}
+
+func (r *recordingStatusUpdater) OnStatusChange(addr string, up bool) {
+	r.events = append(r.events, statusEvent{addr, up})
+}
+
+// TestBackendStatusUpdaterNotifiedOnTransitions verifies MarkUnhealthy and
+// MarkHealthy notify a registered StatusUpdater, so a balancer nested inside
+// another (see StatusUpdater's doc comment) hears about UP/DOWN transitions.
+func TestBackendStatusUpdaterNotifiedOnTransitions(t *testing.T) {
+	backend := NewBackend("127.0.0.1:9000")
+	updater := &recordingStatusUpdater{}
+	backend.SetStatusUpdater(updater)
+
+	backend.MarkUnhealthy()
+	backend.MarkHealthy()
+
+	if len(updater.events) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(updater.events), updater.events)
+	}
+
+	if updater.events[0] != (statusEvent{addr: "127.0.0.1:9000", up: false}) {
+		t.Fatalf("first event = %+v, want down", updater.events[0])
+	}
+
+	if updater.events[1] != (statusEvent{addr: "127.0.0.1:9000", up: true}) {
+		t.Fatalf("second event = %+v, want up", updater.events[1])
+	}
+}
@@ -0,0 +1,231 @@
+package golb
+
+import (
+	"container/heap"
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+)
+
+// WeightedURL pairs a backend URL with its distribution weight. Zero and
+// negative weights are skipped: that backend never receives traffic.
+type WeightedURL struct {
+	URL    *url.URL
+	Weight float64
+}
+
+// edfEntry is one scheduled backend in the EDF heap.
+type edfEntry struct {
+	backend  *BackendImpl
+	weight   float64
+	deadline float64
+	inflight atomic.Int64 // mirrors BackendImpl.activeRequests, for least-connections+weight modes.
+}
+
+// edfHeap implements a min-heap ordered by deadline.
+type edfHeap []*edfEntry
+
+// Len returns the number of entries in the heap.
+func (h edfHeap) Len() int {
+	return len(h)
+}
+
+// Less orders entries by their earliest deadline first.
+func (h edfHeap) Less(i, j int) bool {
+	return h[i].deadline < h[j].deadline
+}
+
+// Swap swaps two entries in the heap.
+func (h edfHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+// Push adds a new entry to the heap.
+func (h *edfHeap) Push(x interface{}) {
+	entry, ok := x.(*edfEntry)
+	if !ok {
+		return
+	}
+
+	*h = append(*h, entry)
+}
+
+// Pop removes and returns the last entry from the heap.
+func (h *edfHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+
+	return x
+}
+
+// Ensure WeightedBalancer implements the Backend and Balancer interfaces.
+var (
+	_ Backend  = (*WeightedBalancer)(nil)
+	_ Balancer = (*WeightedBalancer)(nil)
+)
+
+// defaultWeight is used by UpsertServer when the caller passes no WithWeight option.
+const defaultWeight = 1
+
+// WeightedBalancer distributes requests across backends proportionally to
+// their weight, using an Earliest-Deadline-First scheduler backed by a
+// min-heap: O(log n) per selection, smooth distribution for floating-point
+// weights (unlike plain round-robin).
+type WeightedBalancer struct {
+	heap    *edfHeap
+	mu      sync.Mutex
+	retry   RetryPolicy // retry/backoff policy applied by Invoke.
+	metrics Metrics     // applied to every backend, including ones added later.
+}
+
+// NewWeightedLoadBalancer creates a WeightedBalancer from a list of weighted
+// backend URLs. Entries with zero or negative weight are skipped. An
+// optional RetryPolicy overrides DefaultRetryPolicy().
+func NewWeightedLoadBalancer(urls []WeightedURL, policy ...RetryPolicy) *WeightedBalancer {
+	h := &edfHeap{}
+	heap.Init(h)
+
+	for _, wu := range urls {
+		if wu.Weight <= 0 {
+			continue
+		}
+
+		entry := &edfEntry{
+			backend:  NewBackend(wu.URL.Host),
+			weight:   wu.Weight,
+			deadline: 1 / wu.Weight,
+		}
+
+		heap.Push(h, entry)
+	}
+
+	return &WeightedBalancer{heap: h, retry: resolveRetryPolicy(policy), metrics: NoopMetrics}
+}
+
+// GetNextServer pops the entry with the smallest deadline, advances its
+// deadline by 1/weight, pushes it back, and returns its backend.
+func (b *WeightedBalancer) GetNextServer() *BackendImpl {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.heap.Len() == 0 {
+		return nil // no servers available.
+	}
+
+	entry, ok := heap.Pop(b.heap).(*edfEntry)
+	if !ok {
+		return nil
+	}
+
+	entry.deadline += 1 / entry.weight
+	heap.Push(b.heap, entry)
+
+	return entry.backend
+}
+
+// Invoke sends the request to the next scheduled backend, retrying against
+// the next-scheduled backend on transient failures per b.retry.
+func (b *WeightedBalancer) Invoke(ctx context.Context, req Request) (Response, error) {
+	return invokeWithRetry(ctx, b.retry, b.GetNextServer, func(tryCtx context.Context, backend *BackendImpl) (Response, error) {
+		entry := b.entryFor(backend)
+		if entry != nil {
+			entry.inflight.Add(1)
+			defer entry.inflight.Add(-1)
+		}
+
+		return backend.Invoke(tryCtx, req)
+	})
+}
+
+// SetMetrics wires m into every backend, including ones added afterwards.
+func (b *WeightedBalancer) SetMetrics(m Metrics) {
+	b.mu.Lock()
+	b.metrics = m
+	backends := make([]*BackendImpl, len(*b.heap))
+	for i, entry := range *b.heap {
+		backends[i] = entry.backend
+	}
+	b.mu.Unlock()
+
+	for _, backend := range backends {
+		backend.SetMetrics(m)
+	}
+}
+
+// UpsertServer adds u with the weight from WithWeight (default 1) if it is
+// not already present. Calling it again for an existing u.Host updates its
+// weight in place.
+func (b *WeightedBalancer) UpsertServer(u *url.URL, opts ...ServerOption) error {
+	cfg := resolveServerConfig(opts)
+	weight := cfg.weight
+	if weight <= 0 {
+		weight = defaultWeight
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, entry := range *b.heap {
+		if entry.backend.addr == u.Host {
+			entry.weight = weight
+			return nil
+		}
+	}
+
+	backend := NewBackend(u.Host)
+	backend.SetMetrics(b.metrics)
+
+	heap.Push(b.heap, &edfEntry{
+		backend:  backend,
+		weight:   weight,
+		deadline: 1 / weight,
+	})
+
+	return nil
+}
+
+// RemoveServer removes the entry for u, if present.
+func (b *WeightedBalancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, entry := range *b.heap {
+		if entry.backend.addr == u.Host {
+			heap.Remove(b.heap, i)
+			return nil
+		}
+	}
+
+	return ErrBackendNotFound
+}
+
+// Servers lists the currently registered backend URLs.
+func (b *WeightedBalancer) Servers() []*url.URL {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	urls := make([]*url.URL, len(*b.heap))
+	for i, entry := range *b.heap {
+		urls[i] = &url.URL{Host: entry.backend.addr}
+	}
+
+	return urls
+}
+
+// entryFor looks up the heap entry for backend, used to track inflight
+// requests alongside the EDF scheduling state.
+func (b *WeightedBalancer) entryFor(backend *BackendImpl) *edfEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, entry := range *b.heap {
+		if entry.backend == backend {
+			return entry
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package golb
+
+import (
+	"net/url"
+	"testing"
+)
+
+// TestWeightedBalancerDistributesByWeight verifies the EDF scheduler spreads
+// selections across backends proportionally to their weight.
+func TestWeightedBalancerDistributesByWeight(t *testing.T) {
+	b := NewWeightedLoadBalancer([]WeightedURL{
+		{URL: &url.URL{Host: "a"}, Weight: 1},
+		{URL: &url.URL{Host: "b"}, Weight: 3},
+	})
+
+	const picks = 400
+
+	counts := make(map[string]int)
+	for i := 0; i < picks; i++ {
+		counts[b.GetNextServer().Host()]++
+	}
+
+	wantA, wantB := picks/4, picks*3/4
+	tolerance := picks / 10
+
+	if diff := abs(counts["a"] - wantA); diff > tolerance {
+		t.Fatalf("backend a got %d picks over %d, want ~%d (+/-%d)", counts["a"], picks, wantA, tolerance)
+	}
+
+	if diff := abs(counts["b"] - wantB); diff > tolerance {
+		t.Fatalf("backend b got %d picks over %d, want ~%d (+/-%d)", counts["b"], picks, wantB, tolerance)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}